@@ -0,0 +1,300 @@
+package sparql
+
+import (
+	"bytes"
+	"container/list"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// CacheEntry is a cached HTTP response, keyed on the request's endpoint,
+// query, and Accept type.
+type CacheEntry struct {
+	StatusCode int
+	Header     http.Header
+	Body       []byte
+	Expires    time.Time
+}
+
+func (e CacheEntry) toResponse(req *http.Request) *http.Response {
+	return &http.Response{
+		StatusCode: e.StatusCode,
+		Status:     http.StatusText(e.StatusCode),
+		Header:     e.Header,
+		Body:       io.NopCloser(bytes.NewReader(e.Body)),
+		Request:    req,
+	}
+}
+
+// Cache stores and retrieves CacheEntry values keyed by an opaque cache
+// key built from a request's endpoint, query, and Accept type.
+type Cache interface {
+	Get(key string) (CacheEntry, bool)
+	Set(key string, entry CacheEntry)
+}
+
+// NewLRUCache returns an in-memory Cache holding at most capacity
+// entries, evicting the least recently used entry once full.
+func NewLRUCache(capacity int) Cache {
+	return &lruCache{
+		capacity: capacity,
+		entries:  make(map[string]*list.Element),
+		order:    list.New(),
+	}
+}
+
+type lruCache struct {
+	mu       sync.Mutex
+	capacity int
+	entries  map[string]*list.Element
+	order    *list.List
+}
+
+type lruItem struct {
+	key   string
+	entry CacheEntry
+}
+
+func (c *lruCache) Get(key string) (CacheEntry, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	el, ok := c.entries[key]
+	if !ok {
+		return CacheEntry{}, false
+	}
+	c.order.MoveToFront(el)
+	return el.Value.(*lruItem).entry, true
+}
+
+func (c *lruCache) Set(key string, entry CacheEntry) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if el, ok := c.entries[key]; ok {
+		el.Value.(*lruItem).entry = entry
+		c.order.MoveToFront(el)
+		return
+	}
+
+	el := c.order.PushFront(&lruItem{key: key, entry: entry})
+	c.entries[key] = el
+	if c.order.Len() > c.capacity {
+		oldest := c.order.Back()
+		if oldest != nil {
+			c.order.Remove(oldest)
+			delete(c.entries, oldest.Value.(*lruItem).key)
+		}
+	}
+}
+
+// CacheOption configures the RoundTripper installed by WithCache.
+type CacheOption func(*cachingTransport)
+
+// NegativeTTL caches error and 5xx/429 responses for ttl, so a flapping
+// endpoint isn't hammered with identical failing queries.
+func NegativeTTL(ttl time.Duration) CacheOption {
+	return func(t *cachingTransport) {
+		t.negativeTTL = ttl
+	}
+}
+
+// Singleflight coalesces concurrent identical in-flight requests into a
+// single HTTP call, fanning the shared result out to every caller.
+func Singleflight(enabled bool) CacheOption {
+	return func(t *cachingTransport) {
+		t.singleflight = enabled
+	}
+}
+
+// WithCache wraps the client's current RoundTripper with cache,
+// keyed on each request's endpoint, query, and Accept type. It
+// revalidates stale entries with If-None-Match/If-Modified-Since,
+// honors Cache-Control max-age/no-store from the endpoint, and serves
+// the cached response on 304 Not Modified.
+func WithCache(cache Cache, opts ...CacheOption) Option {
+	return func(c *Client) error {
+		t := &cachingTransport{next: c.HTTPClient.Transport, cache: cache}
+		for _, opt := range opts {
+			opt(t)
+		}
+		c.HTTPClient.Transport = t
+		return nil
+	}
+}
+
+type cachingTransport struct {
+	next         http.RoundTripper
+	cache        Cache
+	negativeTTL  time.Duration
+	singleflight bool
+
+	mu       sync.Mutex
+	inflight map[string]*inflightCall
+}
+
+type inflightCall struct {
+	done chan struct{}
+	resp *http.Response
+	body []byte
+	err  error
+}
+
+func (c *inflightCall) response() *http.Response {
+	resp := *c.resp
+	resp.Body = io.NopCloser(bytes.NewReader(c.body))
+	return &resp
+}
+
+func (t *cachingTransport) Unwrap() http.RoundTripper {
+	return t.next
+}
+
+func (t *cachingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	key := cacheKey(req)
+
+	if entry, ok := t.cache.Get(key); ok && time.Now().Before(entry.Expires) {
+		return entry.toResponse(req), nil
+	}
+
+	if t.singleflight {
+		return t.roundTripOnce(key, req)
+	}
+	return t.doRoundTrip(key, req)
+}
+
+func (t *cachingTransport) roundTripOnce(key string, req *http.Request) (*http.Response, error) {
+	t.mu.Lock()
+	if t.inflight == nil {
+		t.inflight = make(map[string]*inflightCall)
+	}
+	if call, ok := t.inflight[key]; ok {
+		t.mu.Unlock()
+		<-call.done
+		if call.err != nil {
+			return nil, call.err
+		}
+		return call.response(), nil
+	}
+	call := &inflightCall{done: make(chan struct{})}
+	t.inflight[key] = call
+	t.mu.Unlock()
+
+	resp, err := t.doRoundTrip(key, req)
+	if err == nil {
+		call.body, err = io.ReadAll(resp.Body)
+		resp.Body.Close()
+		call.resp = resp
+	}
+	call.err = err
+	close(call.done)
+
+	t.mu.Lock()
+	delete(t.inflight, key)
+	t.mu.Unlock()
+
+	if err != nil {
+		return nil, err
+	}
+	return call.response(), nil
+}
+
+func (t *cachingTransport) doRoundTrip(key string, req *http.Request) (*http.Response, error) {
+	if entry, ok := t.cache.Get(key); ok {
+		if etag := entry.Header.Get("ETag"); etag != "" {
+			req.Header.Set("If-None-Match", etag)
+		}
+		if lm := entry.Header.Get("Last-Modified"); lm != "" {
+			req.Header.Set("If-Modified-Since", lm)
+		}
+	}
+
+	resp, err := t.next.RoundTrip(req)
+	if err != nil {
+		if t.negativeTTL > 0 {
+			t.cache.Set(key, CacheEntry{StatusCode: http.StatusBadGateway, Expires: time.Now().Add(t.negativeTTL)})
+		}
+		return nil, err
+	}
+
+	if resp.StatusCode == http.StatusNotModified {
+		resp.Body.Close()
+		if entry, ok := t.cache.Get(key); ok {
+			// The 304 itself is only sent because our prior entry had
+			// already lapsed (that's why doRoundTrip, not the freshness
+			// check in RoundTrip, handled this request), so refresh its
+			// headers and expiry from this response before reusing it -
+			// otherwise every future call would repeat this same
+			// conditional round-trip forever. entry.Header is the same
+			// map instance the cache may be concurrently handing out, so
+			// mutate a clone, not the stored headers themselves.
+			header := entry.Header.Clone()
+			for name, values := range resp.Header {
+				header[name] = values
+			}
+			entry.Header = header
+
+			age := maxAge(resp.Header)
+			if resp.Header.Get("Cache-Control") == "" {
+				// Many servers only resend Cache-Control on 200, not on
+				// 304; fall back to the prior entry's freshness window
+				// rather than treating a silent 304 as immediately stale.
+				age = maxAge(entry.Header)
+			}
+			entry.Expires = time.Now().Add(age)
+
+			t.cache.Set(key, entry)
+			return entry.toResponse(req), nil
+		}
+	}
+
+	if strings.Contains(resp.Header.Get("Cache-Control"), "no-store") {
+		return resp, nil
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+
+	if resp.StatusCode >= http.StatusInternalServerError || resp.StatusCode == http.StatusTooManyRequests {
+		if t.negativeTTL > 0 {
+			t.cache.Set(key, CacheEntry{StatusCode: resp.StatusCode, Header: resp.Header, Body: body, Expires: time.Now().Add(t.negativeTTL)})
+		}
+		return resp, nil
+	}
+
+	t.cache.Set(key, CacheEntry{
+		StatusCode: resp.StatusCode,
+		Header:     resp.Header,
+		Body:       body,
+		Expires:    time.Now().Add(maxAge(resp.Header)),
+	})
+	return resp, nil
+}
+
+// maxAge returns the max-age directive from a Cache-Control header, or
+// zero if absent or unparsable.
+func maxAge(header http.Header) time.Duration {
+	for _, directive := range strings.Split(header.Get("Cache-Control"), ",") {
+		directive = strings.TrimSpace(directive)
+		if strings.HasPrefix(directive, "max-age=") {
+			if n, err := strconv.Atoi(strings.TrimPrefix(directive, "max-age=")); err == nil {
+				return time.Duration(n) * time.Second
+			}
+		}
+	}
+	return 0
+}
+
+// cacheKey builds the (endpoint, query, accept-type) key a request is
+// cached under.
+func cacheKey(req *http.Request) string {
+	return req.URL.String() + "|" + req.Header.Get("Accept")
+}
@@ -0,0 +1,120 @@
+package sparql
+
+import (
+	"io"
+	"net/http"
+	"testing"
+	"time"
+)
+
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+func TestCachingTransportRevalidateFallsBackToPriorMaxAge(t *testing.T) {
+	cache := NewLRUCache(10)
+	key := "http://endpoint/query|application/sparql-results+json"
+	header := make(http.Header)
+	header.Set("ETag", `"v1"`)
+	header.Set("Cache-Control", "max-age=60")
+	cache.Set(key, CacheEntry{
+		StatusCode: http.StatusOK,
+		Header:     header,
+		Body:       []byte("A"),
+		Expires:    time.Now().Add(-time.Minute), // already stale
+	})
+
+	next := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		if etag := req.Header.Get("If-None-Match"); etag != `"v1"` {
+			t.Fatalf("If-None-Match = %q, want %q", etag, `"v1"`)
+		}
+		// Real servers commonly omit Cache-Control on a 304.
+		return &http.Response{StatusCode: http.StatusNotModified, Header: http.Header{}, Body: http.NoBody}, nil
+	})
+
+	transport := &cachingTransport{next: next, cache: cache}
+	req, err := http.NewRequest(http.MethodGet, "http://endpoint/query", nil)
+	if err != nil {
+		t.Fatalf("NewRequest() error = %v", err)
+	}
+
+	resp, err := transport.doRoundTrip(key, req)
+	if err != nil {
+		t.Fatalf("doRoundTrip() error = %v", err)
+	}
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatalf("ReadAll() error = %v", err)
+	}
+	if string(body) != "A" {
+		t.Fatalf("body = %q, want %q", body, "A")
+	}
+
+	entry, ok := cache.Get(key)
+	if !ok {
+		t.Fatal("cache entry missing after revalidation")
+	}
+	if !entry.Expires.After(time.Now()) {
+		t.Fatalf("entry.Expires = %v, want a future time carried over from the prior max-age", entry.Expires)
+	}
+}
+
+func TestCachingTransportRevalidateDoesNotMutateStoredHeader(t *testing.T) {
+	cache := NewLRUCache(10)
+	key := "http://endpoint/query|application/sparql-results+json"
+	stored := make(http.Header)
+	stored.Set("ETag", `"v1"`)
+	cache.Set(key, CacheEntry{
+		StatusCode: http.StatusOK,
+		Header:     stored,
+		Body:       []byte("A"),
+		Expires:    time.Now().Add(-time.Minute),
+	})
+
+	next := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		return &http.Response{
+			StatusCode: http.StatusNotModified,
+			Header:     http.Header{"X-Revalidated": {"yes"}},
+			Body:       http.NoBody,
+		}, nil
+	})
+
+	transport := &cachingTransport{next: next, cache: cache}
+	req, err := http.NewRequest(http.MethodGet, "http://endpoint/query", nil)
+	if err != nil {
+		t.Fatalf("NewRequest() error = %v", err)
+	}
+
+	if _, err := transport.doRoundTrip(key, req); err != nil {
+		t.Fatalf("doRoundTrip() error = %v", err)
+	}
+
+	if _, ok := stored["X-Revalidated"]; ok {
+		t.Fatal("doRoundTrip mutated the header map already handed out by the cache instead of a clone")
+	}
+}
+
+func TestLRUCacheEvictsLeastRecentlyUsed(t *testing.T) {
+	cache := NewLRUCache(2)
+	cache.Set("a", CacheEntry{Body: []byte("A")})
+	cache.Set("b", CacheEntry{Body: []byte("B")})
+
+	// Touch "a" so "b" becomes the least recently used entry.
+	if _, ok := cache.Get("a"); !ok {
+		t.Fatal("expected a to be present")
+	}
+
+	cache.Set("c", CacheEntry{Body: []byte("C")})
+
+	if _, ok := cache.Get("b"); ok {
+		t.Fatal("expected b to be evicted as least recently used")
+	}
+	if _, ok := cache.Get("a"); !ok {
+		t.Fatal("expected a to remain cached")
+	}
+	if _, ok := cache.Get("c"); !ok {
+		t.Fatal("expected c to be cached")
+	}
+}
@@ -0,0 +1,42 @@
+package sparql
+
+import (
+	"context"
+	"errors"
+	"net"
+	"testing"
+	"time"
+)
+
+// TestDialerContextCancellation verifies that Dialer plumbs the caller's
+// context into the dial phase, so cancelling it aborts an in-flight
+// handshake instead of waiting for it to finish or time out.
+func TestDialerContextCancellation(t *testing.T) {
+	dialing := make(chan struct{})
+	client, err := New("http://example.invalid", Dialer(func(ctx context.Context, network, addr string) (net.Conn, error) {
+		close(dialing)
+		<-ctx.Done()
+		return nil, ctx.Err()
+	}))
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() {
+		done <- client.Ping(ctx)
+	}()
+
+	<-dialing
+	cancel()
+
+	select {
+	case err := <-done:
+		if !errors.Is(err, context.Canceled) {
+			t.Fatalf("Ping() error = %v, want context.Canceled", err)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("Ping did not return after the dial context was cancelled")
+	}
+}
@@ -0,0 +1,412 @@
+package sparql
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+)
+
+// FederatedClient executes a query against multiple SPARQL endpoints and
+// joins their results locally.
+type FederatedClient struct {
+	endpoints   []*Client
+	fallback    *Client
+	routes      []federatedRoute
+	concurrency int
+	partial     bool
+}
+
+type federatedRoute struct {
+	pattern  string
+	endpoint *Client
+}
+
+// FederatedOption configures a FederatedClient.
+type FederatedOption func(*FederatedClient) error
+
+// NewFederatedClient returns a FederatedClient querying every endpoint
+// in endpoints.
+func NewFederatedClient(endpoints []string, opts ...FederatedOption) (*FederatedClient, error) {
+	fc := &FederatedClient{concurrency: 1}
+	for _, endpoint := range endpoints {
+		client, err := New(endpoint)
+		if err != nil {
+			return nil, err
+		}
+		fc.endpoints = append(fc.endpoints, client)
+	}
+	for _, opt := range opts {
+		if err := opt(fc); err != nil {
+			return nil, err
+		}
+	}
+	return fc, nil
+}
+
+// Concurrency bounds how many endpoints Query dispatches to in
+// parallel. The default is 1.
+func Concurrency(n int) FederatedOption {
+	return func(fc *FederatedClient) error {
+		fc.concurrency = n
+		return nil
+	}
+}
+
+// PartialResults, when allow is true, makes Query return whatever rows
+// it collected alongside a *MultiError instead of failing outright when
+// some endpoints error.
+func PartialResults(allow bool) FederatedOption {
+	return func(fc *FederatedClient) error {
+		fc.partial = allow
+		return nil
+	}
+}
+
+// Route declares that triples matching pattern, a predicate or graph
+// IRI appearing in the query text, live on endpoint, so Query can
+// target it directly instead of broadcasting to every endpoint.
+func Route(pattern string, endpoint string) FederatedOption {
+	return func(fc *FederatedClient) error {
+		client, err := New(endpoint)
+		if err != nil {
+			return err
+		}
+		fc.routes = append(fc.routes, federatedRoute{pattern: pattern, endpoint: client})
+		return nil
+	}
+}
+
+// Fallback sets the endpoint that evaluates a query rewritten with
+// SERVICE blocks, so the graph patterns that match no Route run there.
+// A query that matches no Route at all is broadcast to every endpoint
+// instead, and Fallback is not used for it.
+func Fallback(endpoint string) FederatedOption {
+	return func(fc *FederatedClient) error {
+		client, err := New(endpoint)
+		if err != nil {
+			return err
+		}
+		fc.fallback = client
+		return nil
+	}
+}
+
+// MultiError collects one error per failing endpoint.
+type MultiError struct {
+	Errors map[string]error
+}
+
+func (e *MultiError) Error() string {
+	var b strings.Builder
+	for endpoint, err := range e.Errors {
+		fmt.Fprintf(&b, "%s: %v; ", endpoint, err)
+	}
+	return strings.TrimSuffix(b.String(), "; ")
+}
+
+// FederatedRow is one result row merged from a federated query's
+// endpoints.
+type FederatedRow map[string]interface{}
+
+// Query executes a federated query. When one or more Route declarations
+// match a graph pattern in query's WHERE clause, that pattern is
+// rewritten into a SPARQL SERVICE <endpoint> block and the whole,
+// rewritten query is sent once to the execution endpoint (Fallback, or
+// the first configured endpoint when Fallback is unset), which
+// evaluates the SERVICE clauses and joins their results itself, per the
+// SPARQL 1.1 Federated Query extension. When no Route matches anything
+// in query, Query instead broadcasts the unmodified query to every
+// endpoint, concurrently, bounded by Concurrency, and concatenates
+// their rows — a plain union, appropriate only when every endpoint can
+// answer the query on its own (e.g. replicated data), not when the data
+// is partitioned across endpoints and needs a local join. When
+// PartialResults is enabled, per-endpoint failures in the broadcast
+// case are returned as a *MultiError alongside whatever rows the other
+// endpoints produced; otherwise any endpoint failure fails the whole
+// query.
+func (fc *FederatedClient) Query(ctx context.Context, query string, params ...interface{}) ([]FederatedRow, error) {
+	if len(params) > 0 {
+		query = fmt.Sprintf(query, params...)
+	}
+
+	if rewritten, ok := fc.rewriteQuery(query); ok {
+		target := fc.executionEndpoint()
+		rows, err := target.queryJSON(ctx, rewritten)
+		if err != nil {
+			return nil, &MultiError{Errors: map[string]error{target.Endpoint: err}}
+		}
+		return rows, nil
+	}
+
+	return fc.broadcast(ctx, query)
+}
+
+// executionEndpoint returns the endpoint that evaluates a query rewritten
+// with SERVICE blocks.
+func (fc *FederatedClient) executionEndpoint() *Client {
+	if fc.fallback != nil {
+		return fc.fallback
+	}
+	return fc.endpoints[0]
+}
+
+// rewriteQuery wraps every top-level graph pattern in query's WHERE
+// clause that matches a Route in a SERVICE <endpoint> block naming that
+// route's endpoint. ok is false, and query is returned unchanged, when
+// no Route matches anything in the WHERE clause.
+func (fc *FederatedClient) rewriteQuery(query string) (rewritten string, ok bool) {
+	start, end, found := whereBlock(query)
+	if !found {
+		return query, false
+	}
+
+	statements := splitStatements(query[start+1 : end])
+	matched := false
+	for i, stmt := range statements {
+		trimmed := strings.TrimSpace(stmt)
+		if trimmed == "" {
+			continue
+		}
+		if endpoint, routeOK := fc.routeFor(trimmed); routeOK {
+			statements[i] = fmt.Sprintf(" SERVICE <%s> { %s }", endpoint, trimmed)
+			matched = true
+		}
+	}
+	if !matched {
+		return query, false
+	}
+
+	return query[:start+1] + strings.Join(statements, " .\n") + query[end:], true
+}
+
+// routeFor returns the endpoint declared with Route for the first route
+// whose pattern appears in stmt, a single graph pattern from a WHERE
+// clause.
+func (fc *FederatedClient) routeFor(stmt string) (endpoint string, ok bool) {
+	for _, route := range fc.routes {
+		if strings.Contains(stmt, route.pattern) {
+			return route.endpoint.Endpoint, true
+		}
+	}
+	return "", false
+}
+
+// whereBlock returns the byte offsets of query's outermost graph
+// pattern — the { ... } block following SELECT/ASK/CONSTRUCT/DESCRIBE,
+// with or without an explicit WHERE keyword — or ok=false if query has
+// no top-level brace block.
+func whereBlock(query string) (start, end int, ok bool) {
+	depth := 0
+	start = -1
+	templateSkipped := false
+	for i, r := range query {
+		switch r {
+		case '{':
+			if depth == 0 {
+				start = i
+			}
+			depth++
+		case '}':
+			depth--
+			if depth == 0 && start >= 0 {
+				// A CONSTRUCT template comes before the WHERE clause.
+				head := strings.ToUpper(query[:start])
+				if !templateSkipped && strings.Contains(head, "CONSTRUCT") && !strings.Contains(head, "WHERE") {
+					templateSkipped = true
+					start = -1
+					continue
+				}
+				return start, i, true
+			}
+		}
+	}
+	return 0, 0, false
+}
+
+// splitStatements splits body on top-level "." statement terminators,
+// leaving alone any "." that is: nested inside braces (e.g. OPTIONAL
+// { ... }) or parens (e.g. FILTER(...)), part of an IRIREF (<...>) or
+// string literal, or the decimal point of a numeric literal like 10.5.
+func splitStatements(body string) []string {
+	runes := []rune(body)
+	n := len(runes)
+
+	var statements []string
+	brace, paren := 0, 0
+	last := 0
+	for i := 0; i < n; i++ {
+		switch runes[i] {
+		case '<':
+			i = skipIRIRef(runes, i)
+		case '"', '\'':
+			i = skipStringLiteral(runes, i)
+		case '{':
+			brace++
+		case '}':
+			brace--
+		case '(':
+			paren++
+		case ')':
+			paren--
+		case '.':
+			if brace == 0 && paren == 0 && !isDecimalPoint(runes, i) {
+				statements = append(statements, string(runes[last:i]))
+				last = i + 1
+			}
+		}
+	}
+	if last < n {
+		statements = append(statements, string(runes[last:]))
+	}
+	return statements
+}
+
+// skipIRIRef returns the index of the closing '>' of the IRIREF
+// starting at the '<' index start, or the end of runes if it is
+// unterminated. It returns start itself when the '<' cannot begin an
+// IRIREF, such as a less-than operator followed by a space.
+func skipIRIRef(runes []rune, start int) int {
+	for i := start + 1; i < len(runes); i++ {
+		if runes[i] == '>' {
+			return i
+		}
+		if runes[i] <= ' ' || strings.ContainsRune("<\"{}|^`\\", runes[i]) {
+			return start
+		}
+	}
+	return len(runes) - 1
+}
+
+// skipStringLiteral returns the index of the closing quote of the
+// string literal starting at the quote index start, handling both
+// short ("...", '...') and long ("""...""", '''...''') forms.
+func skipStringLiteral(runes []rune, start int) int {
+	quote := runes[start]
+	n := len(runes)
+
+	if start+2 < n && runes[start+1] == quote && runes[start+2] == quote {
+		for i := start + 3; i+2 < n; i++ {
+			if runes[i] == quote && runes[i+1] == quote && runes[i+2] == quote {
+				return i + 2
+			}
+		}
+		return n - 1
+	}
+
+	for i := start + 1; i < n; i++ {
+		if runes[i] == '\\' {
+			i++
+			continue
+		}
+		if runes[i] == quote {
+			return i
+		}
+	}
+	return n - 1
+}
+
+// isDecimalPoint reports whether the "." at index i sits between two
+// digits, as in a decimal literal like 10.5, rather than terminating a
+// statement.
+func isDecimalPoint(runes []rune, i int) bool {
+	return i > 0 && i+1 < len(runes) && isDigit(runes[i-1]) && isDigit(runes[i+1])
+}
+
+func isDigit(r rune) bool {
+	return r >= '0' && r <= '9'
+}
+
+// broadcast sends the unmodified query to every endpoint concurrently,
+// bounded by Concurrency, and concatenates their rows.
+func (fc *FederatedClient) broadcast(ctx context.Context, query string) ([]FederatedRow, error) {
+	concurrency := fc.concurrency
+	if concurrency <= 0 {
+		concurrency = 1
+	}
+
+	type result struct {
+		endpoint string
+		rows     []FederatedRow
+		err      error
+	}
+	results := make(chan result, len(fc.endpoints))
+	sem := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+	for _, client := range fc.endpoints {
+		wg.Add(1)
+		go func(client *Client) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+			rows, err := client.queryJSON(ctx, query)
+			results <- result{endpoint: client.Endpoint, rows: rows, err: err}
+		}(client)
+	}
+	wg.Wait()
+	close(results)
+
+	var rows []FederatedRow
+	errs := map[string]error{}
+	for r := range results {
+		if r.err != nil {
+			errs[r.endpoint] = r.err
+			continue
+		}
+		rows = append(rows, r.rows...)
+	}
+
+	if len(errs) == 0 {
+		return rows, nil
+	}
+	multiErr := &MultiError{Errors: errs}
+	if fc.partial {
+		return rows, multiErr
+	}
+	return nil, multiErr
+}
+
+// queryJSON executes query against this endpoint using the SPARQL 1.1
+// Query Results JSON Format and decodes it into federated rows.
+func (c *Client) queryJSON(ctx context.Context, query string) ([]FederatedRow, error) {
+	endpoint := c.Endpoint + "?" + url.Values{"query": {query}}.Encode()
+	request, err := http.NewRequest(http.MethodGet, endpoint, nil)
+	if err != nil {
+		return nil, err
+	}
+	request.Header.Set("Accept", "application/sparql-results+json")
+
+	resp, err := c.HTTPClient.Do(request.WithContext(ctx))
+	if err != nil {
+		return nil, err
+	}
+	defer c.Logger.LogCloseError(resp.Body)
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("SPARQL query error. status code %d", resp.StatusCode)
+	}
+
+	var parsed struct {
+		Results struct {
+			Bindings []map[string]struct {
+				Value string `json:"value"`
+			} `json:"bindings"`
+		} `json:"results"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&parsed); err != nil {
+		return nil, err
+	}
+
+	rows := make([]FederatedRow, 0, len(parsed.Results.Bindings))
+	for _, binding := range parsed.Results.Bindings {
+		row := make(FederatedRow, len(binding))
+		for variable, term := range binding {
+			row[variable] = term.Value
+		}
+		rows = append(rows, row)
+	}
+	return rows, nil
+}
@@ -0,0 +1,33 @@
+package sparql
+
+import "testing"
+
+func TestSplitStatementsIgnoresDecimalPoints(t *testing.T) {
+	body := ` ?s :price ?price . FILTER(?price > 10.5) `
+	got := splitStatements(body)
+	want := []string{" ?s :price ?price ", " FILTER(?price > 10.5) "}
+
+	if len(got) != len(want) {
+		t.Fatalf("splitStatements(%q) = %q, want %q", body, got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("splitStatements(%q)[%d] = %q, want %q", body, i, got[i], want[i])
+		}
+	}
+}
+
+func TestSplitStatementsIgnoresDotsInIRIsAndLiterals(t *testing.T) {
+	body := ` ?s <http://ex/v1.0/X> "a.b" . ?s :q ?o `
+	got := splitStatements(body)
+	want := []string{` ?s <http://ex/v1.0/X> "a.b" `, " ?s :q ?o "}
+
+	if len(got) != len(want) {
+		t.Fatalf("splitStatements(%q) = %q, want %q", body, got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("splitStatements(%q)[%d] = %q, want %q", body, i, got[i], want[i])
+		}
+	}
+}
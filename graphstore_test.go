@@ -0,0 +1,48 @@
+package sparql
+
+import "testing"
+
+func TestContentTypeForDefaultsAndOverrides(t *testing.T) {
+	if ct := contentTypeFor(FormatNTriples); ct != "application/n-triples" {
+		t.Fatalf("contentTypeFor(FormatNTriples) = %q, want %q", ct, "application/n-triples")
+	}
+	if ct := contentTypeFor(RDFFormat("unregistered")); ct != "text/turtle" {
+		t.Fatalf("contentTypeFor(unregistered) = %q, want %q", ct, "text/turtle")
+	}
+
+	RegisterGraphFormat(FormatTurtle, "text/turtle; charset=utf-8")
+	defer RegisterGraphFormat(FormatTurtle, "text/turtle")
+	if ct := contentTypeFor(FormatTurtle); ct != "text/turtle; charset=utf-8" {
+		t.Fatalf("contentTypeFor(FormatTurtle) after override = %q, want %q", ct, "text/turtle; charset=utf-8")
+	}
+}
+
+func TestGraphStoreURLDirectIdentification(t *testing.T) {
+	c := &Client{Endpoint: "http://endpoint/store"}
+
+	if got, want := c.graphStoreURL(""), "http://endpoint/store?default"; got != want {
+		t.Fatalf("graphStoreURL(\"\") = %q, want %q", got, want)
+	}
+	if got, want := c.graphStoreURL("http://example/g"), "http://endpoint/store?graph=http%3A%2F%2Fexample%2Fg"; got != want {
+		t.Fatalf("graphStoreURL(graph) = %q, want %q", got, want)
+	}
+}
+
+func TestGraphStoreURLWithExistingQueryString(t *testing.T) {
+	c := &Client{Endpoint: "http://endpoint/store?tenant=1"}
+
+	if got, want := c.graphStoreURL("http://example/g"), "http://endpoint/store?tenant=1&graph=http%3A%2F%2Fexample%2Fg"; got != want {
+		t.Fatalf("graphStoreURL(graph) = %q, want %q", got, want)
+	}
+}
+
+func TestGraphStoreURLIndirectIdentification(t *testing.T) {
+	c := &Client{Endpoint: "http://endpoint/sparql", graphStoreEndpoint: "http://endpoint/graphs/mine"}
+
+	if got, want := c.graphStoreURL(""), "http://endpoint/graphs/mine"; got != want {
+		t.Fatalf("graphStoreURL(\"\") with GraphStoreEndpoint = %q, want %q", got, want)
+	}
+	if got, want := c.graphStoreURL("http://example/g"), "http://endpoint/graphs/mine?graph=http%3A%2F%2Fexample%2Fg"; got != want {
+		t.Fatalf("graphStoreURL(graph) with GraphStoreEndpoint = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,242 @@
+package sparql
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// WithBasicAuth wraps the client's current RoundTripper so every request
+// carries HTTP Basic credentials.
+func WithBasicAuth(username, password string) Option {
+	return func(c *Client) error {
+		c.HTTPClient.Transport = &basicAuthTransport{
+			next:     c.HTTPClient.Transport,
+			username: username,
+			password: password,
+		}
+		return nil
+	}
+}
+
+type basicAuthTransport struct {
+	next     http.RoundTripper
+	username string
+	password string
+}
+
+func (t *basicAuthTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	req = req.Clone(req.Context())
+	req.SetBasicAuth(t.username, t.password)
+	return t.next.RoundTrip(req)
+}
+
+func (t *basicAuthTransport) Unwrap() http.RoundTripper {
+	return t.next
+}
+
+// WithBearerToken wraps the client's current RoundTripper so every
+// request carries an Authorization: Bearer header. token is called
+// before every request, so it can refresh an OAuth2 access token.
+func WithBearerToken(token func(ctx context.Context) (string, error)) Option {
+	return func(c *Client) error {
+		c.HTTPClient.Transport = &bearerTokenTransport{
+			next:  c.HTTPClient.Transport,
+			token: token,
+		}
+		return nil
+	}
+}
+
+type bearerTokenTransport struct {
+	next  http.RoundTripper
+	token func(ctx context.Context) (string, error)
+}
+
+func (t *bearerTokenTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	token, err := t.token(req.Context())
+	if err != nil {
+		return nil, err
+	}
+	req = req.Clone(req.Context())
+	req.Header.Set("Authorization", "Bearer "+token)
+	return t.next.RoundTrip(req)
+}
+
+func (t *bearerTokenTransport) Unwrap() http.RoundTripper {
+	return t.next
+}
+
+// RetryPolicy configures WithRetry's exponential backoff.
+type RetryPolicy struct {
+	// MaxRetries is the number of retry attempts after the first try.
+	MaxRetries int
+	// BaseDelay is the backoff delay before the first retry. It doubles
+	// on each subsequent attempt, up to MaxDelay.
+	BaseDelay time.Duration
+	// MaxDelay caps the backoff delay. Zero means uncapped.
+	MaxDelay time.Duration
+}
+
+// WithRetry wraps the client's current RoundTripper with exponential
+// backoff and jitter on 5xx and 429 responses, honoring a Retry-After
+// response header when present.
+func WithRetry(policy RetryPolicy) Option {
+	return func(c *Client) error {
+		c.HTTPClient.Transport = &retryTransport{
+			next:   c.HTTPClient.Transport,
+			policy: policy,
+		}
+		return nil
+	}
+}
+
+type retryTransport struct {
+	next   http.RoundTripper
+	policy RetryPolicy
+}
+
+func (t *retryTransport) Unwrap() http.RoundTripper {
+	return t.next
+}
+
+func (t *retryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	var body []byte
+	if req.Body != nil {
+		var err error
+		if body, err = io.ReadAll(req.Body); err != nil {
+			return nil, err
+		}
+		req.Body.Close()
+	}
+
+	var resp *http.Response
+	var err error
+	for attempt := 0; ; attempt++ {
+		if body != nil {
+			req.Body = io.NopCloser(bytes.NewReader(body))
+		}
+		resp, err = t.next.RoundTrip(req)
+		if err == nil && !shouldRetry(resp.StatusCode) {
+			return resp, nil
+		}
+		if attempt >= t.policy.MaxRetries {
+			return resp, err
+		}
+
+		delay := t.policy.backoff(attempt)
+		if resp != nil {
+			if ra := retryAfter(resp.Header.Get("Retry-After")); ra > 0 {
+				delay = ra
+			}
+			resp.Body.Close()
+		}
+
+		select {
+		case <-req.Context().Done():
+			return resp, req.Context().Err()
+		case <-time.After(delay):
+		}
+	}
+}
+
+func shouldRetry(status int) bool {
+	return status == http.StatusTooManyRequests || status >= http.StatusInternalServerError
+}
+
+func (p RetryPolicy) backoff(attempt int) time.Duration {
+	delay := p.BaseDelay << uint(attempt)
+	if p.MaxDelay > 0 && delay > p.MaxDelay {
+		delay = p.MaxDelay
+	}
+	return delay/2 + time.Duration(rand.Int63n(int64(delay)/2+1))
+}
+
+func retryAfter(header string) time.Duration {
+	if header == "" {
+		return 0
+	}
+	if secs, err := strconv.Atoi(header); err == nil {
+		return time.Duration(secs) * time.Second
+	}
+	if at, err := http.ParseTime(header); err == nil {
+		return time.Until(at)
+	}
+	return 0
+}
+
+// ErrCircuitOpen is returned by a RoundTripper installed with
+// WithCircuitBreaker when the breaker is open for the request's host.
+var ErrCircuitOpen = errors.New("sparql: circuit breaker open")
+
+// CircuitBreakerConfig configures WithCircuitBreaker.
+type CircuitBreakerConfig struct {
+	// FailureThreshold is the number of consecutive failures, per host,
+	// that opens the breaker.
+	FailureThreshold int
+	// OpenDuration is how long the breaker stays open before allowing a
+	// request through again.
+	OpenDuration time.Duration
+}
+
+// WithCircuitBreaker wraps the client's current RoundTripper with a
+// per-host circuit breaker that sheds load after FailureThreshold
+// consecutive failures, returning ErrCircuitOpen until OpenDuration
+// elapses.
+func WithCircuitBreaker(cfg CircuitBreakerConfig) Option {
+	return func(c *Client) error {
+		c.HTTPClient.Transport = &circuitBreakerTransport{
+			next: c.HTTPClient.Transport,
+			cfg:  cfg,
+		}
+		return nil
+	}
+}
+
+type circuitBreakerTransport struct {
+	next http.RoundTripper
+	cfg  CircuitBreakerConfig
+
+	mu        sync.Mutex
+	failures  map[string]int
+	openUntil map[string]time.Time
+}
+
+func (t *circuitBreakerTransport) Unwrap() http.RoundTripper {
+	return t.next
+}
+
+func (t *circuitBreakerTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	host := req.URL.Host
+
+	t.mu.Lock()
+	if t.openUntil == nil {
+		t.openUntil = make(map[string]time.Time)
+		t.failures = make(map[string]int)
+	}
+	if until, open := t.openUntil[host]; open && time.Now().Before(until) {
+		t.mu.Unlock()
+		return nil, ErrCircuitOpen
+	}
+	t.mu.Unlock()
+
+	resp, err := t.next.RoundTrip(req)
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if err != nil || resp.StatusCode >= http.StatusInternalServerError {
+		t.failures[host]++
+		if t.failures[host] >= t.cfg.FailureThreshold {
+			t.openUntil[host] = time.Now().Add(t.cfg.OpenDuration)
+		}
+	} else {
+		t.failures[host] = 0
+	}
+	return resp, err
+}
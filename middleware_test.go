@@ -0,0 +1,135 @@
+package sparql
+
+import (
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestRetryPolicyBackoffIsBoundedByMaxDelay(t *testing.T) {
+	policy := RetryPolicy{BaseDelay: 10 * time.Millisecond, MaxDelay: 15 * time.Millisecond}
+
+	for attempt := 0; attempt < 5; attempt++ {
+		delay := policy.backoff(attempt)
+		if delay < 0 || delay > policy.MaxDelay {
+			t.Fatalf("backoff(%d) = %v, want within [0, %v]", attempt, delay, policy.MaxDelay)
+		}
+	}
+}
+
+func TestRetryPolicyBackoffDoublesBeforeCapping(t *testing.T) {
+	policy := RetryPolicy{BaseDelay: 10 * time.Millisecond}
+
+	delay := policy.backoff(2) // BaseDelay << 2 == 40ms
+	min, max := 20*time.Millisecond, 40*time.Millisecond
+	if delay < min || delay > max {
+		t.Fatalf("backoff(2) = %v, want within [%v, %v]", delay, min, max)
+	}
+}
+
+func TestRetryAfterParsesSecondsAndHTTPDate(t *testing.T) {
+	if got, want := retryAfter(""), time.Duration(0); got != want {
+		t.Fatalf("retryAfter(\"\") = %v, want %v", got, want)
+	}
+	if got, want := retryAfter("5"), 5*time.Second; got != want {
+		t.Fatalf("retryAfter(\"5\") = %v, want %v", got, want)
+	}
+
+	future := time.Now().Add(time.Hour).UTC().Format(http.TimeFormat)
+	if got := retryAfter(future); got <= 0 || got > time.Hour {
+		t.Fatalf("retryAfter(%q) = %v, want a positive duration up to 1h", future, got)
+	}
+}
+
+func TestRetryTransportRetriesUntilSuccess(t *testing.T) {
+	attempts := 0
+	next := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		attempts++
+		if attempts < 3 {
+			return &http.Response{StatusCode: http.StatusServiceUnavailable, Header: http.Header{}, Body: http.NoBody}, nil
+		}
+		return &http.Response{StatusCode: http.StatusOK, Header: http.Header{}, Body: http.NoBody}, nil
+	})
+
+	transport := &retryTransport{next: next, policy: RetryPolicy{MaxRetries: 5, BaseDelay: time.Millisecond}}
+	req, err := http.NewRequest(http.MethodGet, "http://endpoint/query", nil)
+	if err != nil {
+		t.Fatalf("NewRequest() error = %v", err)
+	}
+
+	resp, err := transport.RoundTrip(req)
+	if err != nil {
+		t.Fatalf("RoundTrip() error = %v", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("StatusCode = %d, want %d", resp.StatusCode, http.StatusOK)
+	}
+	if attempts != 3 {
+		t.Fatalf("attempts = %d, want 3", attempts)
+	}
+}
+
+func TestRetryTransportGivesUpAfterMaxRetries(t *testing.T) {
+	attempts := 0
+	next := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		attempts++
+		return &http.Response{StatusCode: http.StatusServiceUnavailable, Header: http.Header{}, Body: http.NoBody}, nil
+	})
+
+	transport := &retryTransport{next: next, policy: RetryPolicy{MaxRetries: 2, BaseDelay: time.Millisecond}}
+	req, err := http.NewRequest(http.MethodGet, "http://endpoint/query", nil)
+	if err != nil {
+		t.Fatalf("NewRequest() error = %v", err)
+	}
+
+	resp, err := transport.RoundTrip(req)
+	if err != nil {
+		t.Fatalf("RoundTrip() error = %v", err)
+	}
+	if resp.StatusCode != http.StatusServiceUnavailable {
+		t.Fatalf("StatusCode = %d, want %d", resp.StatusCode, http.StatusServiceUnavailable)
+	}
+	if attempts != 3 { // first try + 2 retries
+		t.Fatalf("attempts = %d, want 3", attempts)
+	}
+}
+
+func TestCircuitBreakerOpensAfterThresholdAndRecovers(t *testing.T) {
+	failing := true
+	next := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		if failing {
+			return &http.Response{StatusCode: http.StatusInternalServerError, Header: http.Header{}, Body: http.NoBody}, nil
+		}
+		return &http.Response{StatusCode: http.StatusOK, Header: http.Header{}, Body: http.NoBody}, nil
+	})
+
+	transport := &circuitBreakerTransport{
+		next: next,
+		cfg:  CircuitBreakerConfig{FailureThreshold: 2, OpenDuration: 20 * time.Millisecond},
+	}
+	req, err := http.NewRequest(http.MethodGet, "http://endpoint/query", nil)
+	if err != nil {
+		t.Fatalf("NewRequest() error = %v", err)
+	}
+
+	for i := 0; i < 2; i++ {
+		if _, err := transport.RoundTrip(req); err != nil {
+			t.Fatalf("RoundTrip() error = %v", err)
+		}
+	}
+
+	if _, err := transport.RoundTrip(req); err != ErrCircuitOpen {
+		t.Fatalf("RoundTrip() error = %v, want ErrCircuitOpen", err)
+	}
+
+	time.Sleep(25 * time.Millisecond)
+	failing = false
+
+	resp, err := transport.RoundTrip(req)
+	if err != nil {
+		t.Fatalf("RoundTrip() after OpenDuration error = %v", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("StatusCode = %d, want %d", resp.StatusCode, http.StatusOK)
+	}
+}
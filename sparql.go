@@ -19,6 +19,12 @@ type Client struct {
 	dialer     net.Dialer
 	transport  http.Transport
 	prefixes   map[string]URI
+
+	updateEndpoint string
+	updateFormat   UpdateFormat
+
+	graphStoreEndpoint string
+	graphFormat        RDFFormat
 }
 
 // Option sets an option to the SPARQL client.
@@ -58,18 +64,28 @@ func Prefix(prefix string, uri URI) Option {
 	}
 }
 
+// Dialer replaces the connection dialer used for every request, in place
+// of the default net.Dialer configured by Timeout. Use it to inject
+// happy-eyeballs dialers, SOCKS/HTTP proxy dialers, or test doubles. The
+// supplied function receives the request's context, so a cancelled or
+// timed-out context aborts an in-flight dial.
+func Dialer(dial func(ctx context.Context, network, addr string) (net.Conn, error)) Option {
+	return func(c *Client) error {
+		c.transport.DialContext = dial
+		return nil
+	}
+}
+
 // New returns `sparql.Client`.
 func New(endpoint string, opts ...Option) (*Client, error) {
-	dialer := net.Dialer{
-		Timeout:   30 * time.Second,
-		KeepAlive: 30 * time.Second,
-		DualStack: true,
-	}
 	client := &Client{
-		dialer: dialer,
+		dialer: net.Dialer{
+			Timeout:   30 * time.Second,
+			KeepAlive: 30 * time.Second,
+			DualStack: true,
+		},
 		transport: http.Transport{
 			Proxy:                 http.ProxyFromEnvironment,
-			DialContext:           dialer.DialContext,
 			MaxIdleConns:          100,
 			MaxIdleConnsPerHost:   100,
 			IdleConnTimeout:       90 * time.Second,
@@ -80,6 +96,11 @@ func New(endpoint string, opts ...Option) (*Client, error) {
 		Endpoint: endpoint,
 		prefixes: make(map[string]URI),
 	}
+	// Bind DialContext to the client's own dialer field, not a local
+	// copy, so Timeout (which mutates c.dialer after New has returned
+	// this method value) actually changes the dial behavior. Dialer
+	// overrides this wiring entirely when set.
+	client.transport.DialContext = client.dialer.DialContext
 	client.HTTPClient.Transport = &client.transport
 	for _, opt := range opts {
 		if err := opt(client); err != nil {
@@ -89,12 +110,33 @@ func New(endpoint string, opts ...Option) (*Client, error) {
 	return client, nil
 }
 
+// roundTripperUnwrapper is implemented by the RoundTripper middlewares
+// installed by options such as WithBasicAuth, WithRetry, RequestTimeout,
+// and WithCache, so Close can reach the root *http.Transport through
+// however many of them are layered on top of it.
+type roundTripperUnwrapper interface {
+	Unwrap() http.RoundTripper
+}
+
+func rootTransport(rt http.RoundTripper) (*http.Transport, bool) {
+	for {
+		if transport, ok := rt.(*http.Transport); ok {
+			return transport, true
+		}
+		unwrapper, ok := rt.(roundTripperUnwrapper)
+		if !ok {
+			return nil, false
+		}
+		rt = unwrapper.Unwrap()
+	}
+}
+
 // Close closes this client
 func (c *Client) Close() error {
 	if c.HTTPClient.Transport == nil {
 		return errors.New("already closed")
 	}
-	transport, ok := c.HTTPClient.Transport.(*http.Transport)
+	transport, ok := rootTransport(c.HTTPClient.Transport)
 	if !ok {
 		return fmt.Errorf("unknown RoundTripper, %+v", c.HTTPClient.Transport)
 	}
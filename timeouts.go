@@ -0,0 +1,159 @@
+package sparql
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"time"
+)
+
+// TLSHandshakeTimeout sets the maximum amount of time waiting for a TLS
+// handshake. The default is 10 seconds.
+func TLSHandshakeTimeout(timeout time.Duration) Option {
+	return func(c *Client) error {
+		c.transport.TLSHandshakeTimeout = timeout
+		return nil
+	}
+}
+
+// ResponseHeaderTimeout sets the maximum amount of time to wait for a
+// server's response headers after fully writing the request, including
+// its body.
+func ResponseHeaderTimeout(timeout time.Duration) Option {
+	return func(c *Client) error {
+		c.transport.ResponseHeaderTimeout = timeout
+		return nil
+	}
+}
+
+// ExpectContinueTimeout sets the amount of time to wait for a server's
+// first response headers after fully writing the request headers, when
+// the request has an "Expect: 100-continue" header. The default is 1
+// second.
+func ExpectContinueTimeout(timeout time.Duration) Option {
+	return func(c *Client) error {
+		c.transport.ExpectContinueTimeout = timeout
+		return nil
+	}
+}
+
+// MaxConnsPerHost sets the maximum number of connections per host,
+// including connections in the dialing, active, and idle states. Zero
+// means no limit.
+func MaxConnsPerHost(n int) Option {
+	return func(c *Client) error {
+		c.transport.MaxConnsPerHost = n
+		return nil
+	}
+}
+
+// MaxIdleConnsPerHost sets max idle connections per host. The default is
+// 100.
+func MaxIdleConnsPerHost(n int) Option {
+	return func(c *Client) error {
+		c.transport.MaxIdleConnsPerHost = n
+		return nil
+	}
+}
+
+// RequestTimeout wraps the client's current RoundTripper so every
+// round-trip is bound by its own context.WithTimeout of timeout, on top
+// of whatever deadline the caller's context already carries.
+func RequestTimeout(timeout time.Duration) Option {
+	return func(c *Client) error {
+		c.HTTPClient.Transport = &requestTimeoutTransport{
+			next:    c.HTTPClient.Transport,
+			timeout: timeout,
+		}
+		return nil
+	}
+}
+
+type requestTimeoutTransport struct {
+	next    http.RoundTripper
+	timeout time.Duration
+}
+
+func (t *requestTimeoutTransport) Unwrap() http.RoundTripper {
+	return t.next
+}
+
+func (t *requestTimeoutTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	ctx, cancel := context.WithTimeout(req.Context(), t.timeout)
+	resp, err := t.next.RoundTrip(req.WithContext(ctx))
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+	resp.Body = &cancelOnClose{ReadCloser: resp.Body, cancel: cancel}
+	return resp, nil
+}
+
+// cancelOnClose cancels its context once the response body is closed,
+// keeping the timeout alive while the body is still being streamed.
+type cancelOnClose struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+func (c *cancelOnClose) Close() error {
+	defer c.cancel()
+	return c.ReadCloser.Close()
+}
+
+// ErrResponseTooLarge is returned when a response body exceeds the limit
+// configured with MaxResponseBytes.
+var ErrResponseTooLarge = errors.New("sparql: response body too large")
+
+// MaxResponseBytes wraps the client's current RoundTripper so reading
+// more than n bytes from a response body returns ErrResponseTooLarge.
+func MaxResponseBytes(n int64) Option {
+	return func(c *Client) error {
+		c.HTTPClient.Transport = &maxResponseBytesTransport{
+			next: c.HTTPClient.Transport,
+			n:    n,
+		}
+		return nil
+	}
+}
+
+type maxResponseBytesTransport struct {
+	next http.RoundTripper
+	n    int64
+}
+
+func (t *maxResponseBytesTransport) Unwrap() http.RoundTripper {
+	return t.next
+}
+
+func (t *maxResponseBytesTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := t.next.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = &limitedBody{r: io.LimitReader(resp.Body, t.n+1), closer: resp.Body, limit: t.n}
+	return resp, nil
+}
+
+// limitedBody errors with ErrResponseTooLarge once more than limit bytes
+// have been read from the underlying body.
+type limitedBody struct {
+	r      io.Reader
+	closer io.Closer
+	limit  int64
+	read   int64
+}
+
+func (l *limitedBody) Read(p []byte) (int, error) {
+	n, err := l.r.Read(p)
+	l.read += int64(n)
+	if l.read > l.limit {
+		return n, ErrResponseTooLarge
+	}
+	return n, err
+}
+
+func (l *limitedBody) Close() error {
+	return l.closer.Close()
+}
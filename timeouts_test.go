@@ -0,0 +1,85 @@
+package sparql
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestMaxResponseBytesLimitsBody(t *testing.T) {
+	next := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Header:     http.Header{},
+			Body:       io.NopCloser(strings.NewReader("0123456789")),
+		}, nil
+	})
+
+	transport := &maxResponseBytesTransport{next: next, n: 5}
+	req, err := http.NewRequest(http.MethodGet, "http://endpoint/query", nil)
+	if err != nil {
+		t.Fatalf("NewRequest() error = %v", err)
+	}
+
+	resp, err := transport.RoundTrip(req)
+	if err != nil {
+		t.Fatalf("RoundTrip() error = %v", err)
+	}
+	defer resp.Body.Close()
+
+	if _, err := io.ReadAll(resp.Body); !errors.Is(err, ErrResponseTooLarge) {
+		t.Fatalf("ReadAll() error = %v, want ErrResponseTooLarge", err)
+	}
+}
+
+func TestMaxResponseBytesAllowsBodyWithinLimit(t *testing.T) {
+	next := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Header:     http.Header{},
+			Body:       io.NopCloser(strings.NewReader("short")),
+		}, nil
+	})
+
+	transport := &maxResponseBytesTransport{next: next, n: 5}
+	req, err := http.NewRequest(http.MethodGet, "http://endpoint/query", nil)
+	if err != nil {
+		t.Fatalf("NewRequest() error = %v", err)
+	}
+
+	resp, err := transport.RoundTrip(req)
+	if err != nil {
+		t.Fatalf("RoundTrip() error = %v", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatalf("ReadAll() error = %v", err)
+	}
+	if string(body) != "short" {
+		t.Fatalf("body = %q, want %q", body, "short")
+	}
+}
+
+func TestRequestTimeoutCancelsSlowRoundTrip(t *testing.T) {
+	next := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		<-req.Context().Done()
+		return nil, req.Context().Err()
+	})
+
+	transport := &requestTimeoutTransport{next: next, timeout: 10 * time.Millisecond}
+	req, err := http.NewRequest(http.MethodGet, "http://endpoint/query", nil)
+	if err != nil {
+		t.Fatalf("NewRequest() error = %v", err)
+	}
+
+	_, err = transport.RoundTrip(req)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("RoundTrip() error = %v, want context.DeadlineExceeded", err)
+	}
+}
@@ -0,0 +1,293 @@
+package sparql
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+// UpdateFormat selects how Update and UpdateBatch encode the update string
+// on the wire, per the SPARQL 1.1 Protocol.
+type UpdateFormat int
+
+const (
+	// UpdateFormatDirect sends the update string as the request body with
+	// Content-Type: application/sparql-update. This is the default.
+	UpdateFormatDirect UpdateFormat = iota
+	// UpdateFormatForm sends the update string URL-encoded in an "update"
+	// form field with Content-Type: application/x-www-form-urlencoded.
+	UpdateFormatForm
+)
+
+// UpdateEndpoint sets a dedicated endpoint for SPARQL 1.1 Update requests.
+// If unset, Update and UpdateBatch send to Client.Endpoint.
+func UpdateEndpoint(endpoint string) Option {
+	return func(c *Client) error {
+		c.updateEndpoint = endpoint
+		return nil
+	}
+}
+
+// WithUpdateFormat selects the wire format used by Update and UpdateBatch.
+func WithUpdateFormat(format UpdateFormat) Option {
+	return func(c *Client) error {
+		c.updateFormat = format
+		return nil
+	}
+}
+
+// Update sends a SPARQL 1.1 Update request. params are formatted into
+// update with fmt.Sprintf before sending, mirroring Query.
+func (c *Client) Update(ctx context.Context, update string, params ...interface{}) error {
+	if len(params) > 0 {
+		update = fmt.Sprintf(update, params...)
+	}
+	update = c.withPrefixes(update)
+
+	request, err := c.newUpdateRequest(ctx, update)
+	if err != nil {
+		return err
+	}
+
+	resp, err := c.HTTPClient.Do(request)
+	if err != nil {
+		return err
+	}
+	defer c.Logger.LogCloseError(resp.Body)
+	c.Logger.Debug.Printf("Update %+v", resp)
+
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusNoContent {
+		return fmt.Errorf("SPARQL update error. status code %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// UpdateBatch concatenates multiple update operations with ";" and sends
+// them as a single SPARQL 1.1 Update request, for stores that execute a
+// batch atomically.
+func (c *Client) UpdateBatch(ctx context.Context, updates ...string) error {
+	return c.Update(ctx, strings.Join(updates, " ;\n"))
+}
+
+// withPrefixes prepends the client's global PREFIX declarations to update.
+func (c *Client) withPrefixes(update string) string {
+	if len(c.prefixes) == 0 {
+		return update
+	}
+	var b strings.Builder
+	for prefix, uri := range c.prefixes {
+		fmt.Fprintf(&b, "PREFIX %s: <%s>\n", prefix, uri)
+	}
+	b.WriteString(update)
+	return b.String()
+}
+
+func (c *Client) updateEndpointURL() string {
+	if c.updateEndpoint != "" {
+		return c.updateEndpoint
+	}
+	return c.Endpoint
+}
+
+func (c *Client) newUpdateRequest(ctx context.Context, update string) (*http.Request, error) {
+	endpoint := c.updateEndpointURL()
+
+	if c.updateFormat == UpdateFormatForm {
+		body := strings.NewReader(url.Values{"update": {update}}.Encode())
+		request, err := http.NewRequest(http.MethodPost, endpoint, body)
+		if err != nil {
+			return nil, err
+		}
+		request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		return request.WithContext(ctx), nil
+	}
+
+	request, err := http.NewRequest(http.MethodPost, endpoint, strings.NewReader(update))
+	if err != nil {
+		return nil, err
+	}
+	request.Header.Set("Content-Type", "application/sparql-update")
+	return request.WithContext(ctx), nil
+}
+
+// Term is a single subject, predicate, or object of a Triple, already
+// escaped for inclusion in update text. Build one with IRI, Var,
+// Literal, LiteralLang, or LiteralType rather than formatting it by
+// hand.
+type Term string
+
+// IRI returns value escaped and wrapped as an IRI term, e.g. <value>.
+func IRI(value string) Term {
+	return Term("<" + escapeIRI(value) + ">")
+}
+
+// Var returns a SPARQL variable term, e.g. ?value, for use in
+// DeleteWhere patterns.
+func Var(name string) Term {
+	return Term("?" + name)
+}
+
+// Literal returns value escaped and quoted as a plain RDF literal.
+func Literal(value string) Term {
+	return Term(`"` + escapeLiteral(value) + `"`)
+}
+
+// LiteralLang returns value escaped and quoted as a language-tagged RDF
+// literal. Any character lang contains outside BCP47's letters, digits,
+// and hyphens is stripped, so a malformed or malicious lang can't break
+// out of the literal.
+func LiteralLang(value, lang string) Term {
+	return Term(`"` + escapeLiteral(value) + `"@` + sanitizeLangTag(lang))
+}
+
+var langTagDisallowed = regexp.MustCompile(`[^A-Za-z0-9-]`)
+
+func sanitizeLangTag(lang string) string {
+	return langTagDisallowed.ReplaceAllString(lang, "")
+}
+
+// LiteralType returns value escaped and quoted as a typed RDF literal.
+func LiteralType(value string, datatype URI) Term {
+	return Term(`"` + escapeLiteral(value) + `"^^<` + escapeIRI(string(datatype)) + `>`)
+}
+
+// iriEscaper percent-encodes the characters an IRIREF may not contain
+// unescaped: angle brackets, whitespace, and other control characters.
+var iriEscaper = strings.NewReplacer(
+	"<", "%3C",
+	">", "%3E",
+	" ", "%20",
+	"\t", "%09",
+	"\n", "%0A",
+	"\r", "%0D",
+)
+
+func escapeIRI(value string) string {
+	var b strings.Builder
+	for _, r := range iriEscaper.Replace(value) {
+		if r < ' ' {
+			fmt.Fprintf(&b, "%%%02X", r)
+			continue
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+var literalEscaper = strings.NewReplacer(
+	`\`, `\\`,
+	`"`, `\"`,
+	"\n", `\n`,
+	"\r", `\r`,
+	"\t", `\t`,
+)
+
+func escapeLiteral(value string) string {
+	return literalEscaper.Replace(value)
+}
+
+// Triple is one triple pattern, built from escaped terms via IRI, Var,
+// Literal, LiteralLang, or LiteralType.
+type Triple struct {
+	Subject   Term
+	Predicate Term
+	Object    Term
+}
+
+func (t Triple) String() string {
+	return fmt.Sprintf("%s %s %s .", t.Subject, t.Predicate, t.Object)
+}
+
+func triplesBlock(triples []Triple) string {
+	lines := make([]string, len(triples))
+	for i, t := range triples {
+		lines[i] = t.String()
+	}
+	return strings.Join(lines, "\n")
+}
+
+// InsertData builds and sends an INSERT DATA update adding triples.
+func (c *Client) InsertData(ctx context.Context, triples ...Triple) error {
+	return c.Update(ctx, fmt.Sprintf("INSERT DATA { %s }", triplesBlock(triples)))
+}
+
+// DeleteData builds and sends a DELETE DATA update removing the given
+// ground triples.
+func (c *Client) DeleteData(ctx context.Context, triples ...Triple) error {
+	return c.Update(ctx, fmt.Sprintf("DELETE DATA { %s }", triplesBlock(triples)))
+}
+
+// DeleteWhere builds and sends a DELETE WHERE update removing every
+// triple matching the given patterns, which may include Var terms.
+func (c *Client) DeleteWhere(ctx context.Context, patterns ...Triple) error {
+	return c.Update(ctx, fmt.Sprintf("DELETE WHERE { %s }", triplesBlock(patterns)))
+}
+
+// Load builds and sends a LOAD update reading source into graph. An empty
+// graph loads into the default graph.
+func (c *Client) Load(ctx context.Context, source string, graph URI) error {
+	if graph == "" {
+		return c.Update(ctx, fmt.Sprintf("LOAD %s", IRI(source)))
+	}
+	return c.Update(ctx, fmt.Sprintf("LOAD %s INTO GRAPH %s", IRI(source), IRI(string(graph))))
+}
+
+// GraphTarget identifies which graph(s) a CLEAR or DROP update targets.
+type GraphTarget struct {
+	graph URI
+	all   bool
+	named bool
+}
+
+// DefaultGraphTarget targets the default graph.
+func DefaultGraphTarget() GraphTarget {
+	return GraphTarget{}
+}
+
+// NamedGraphTarget targets a specific named graph.
+func NamedGraphTarget(graph URI) GraphTarget {
+	return GraphTarget{graph: graph}
+}
+
+// AllGraphsTarget targets every graph, default and named.
+func AllGraphsTarget() GraphTarget {
+	return GraphTarget{all: true}
+}
+
+// AllNamedGraphsTarget targets every named graph.
+func AllNamedGraphsTarget() GraphTarget {
+	return GraphTarget{named: true}
+}
+
+func (t GraphTarget) String() string {
+	switch {
+	case t.all:
+		return "ALL"
+	case t.named:
+		return "NAMED"
+	case t.graph != "":
+		return fmt.Sprintf("GRAPH %s", IRI(string(t.graph)))
+	default:
+		return "DEFAULT"
+	}
+}
+
+// Clear builds and sends a CLEAR update, removing all triples from target
+// without removing the graph itself.
+func (c *Client) Clear(ctx context.Context, target GraphTarget) error {
+	return c.Update(ctx, fmt.Sprintf("CLEAR %s", target))
+}
+
+// Drop builds and sends a DROP update, removing target and its triples.
+func (c *Client) Drop(ctx context.Context, target GraphTarget) error {
+	return c.Update(ctx, fmt.Sprintf("DROP %s", target))
+}
+
+// Create builds and sends a CREATE update, creating a new, empty named
+// graph.
+func (c *Client) Create(ctx context.Context, graph URI) error {
+	return c.Update(ctx, fmt.Sprintf("CREATE GRAPH %s", IRI(string(graph))))
+}